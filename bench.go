@@ -0,0 +1,348 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// benchRun pairs a scheduler name with the Scheduler to run, drawn from
+// the shared schedulers registry so bench doesn't hardcode the list of
+// algorithms.
+type benchRun struct {
+	name      string
+	scheduler Scheduler
+}
+
+type benchResult struct {
+	name       string
+	avgWait    float64
+	avgTurn    float64
+	throughput float64
+	p99Wait    float64
+	wallClock  time.Duration
+	allocs     uint64
+}
+
+// runBench implements the `bench` subcommand: it synthesizes a workload
+// without needing a CSV, runs every registered scheduler over the
+// identical input concurrently, and prints a comparison table of timing
+// and quality metrics. The shape (worker pool producing samples, shared
+// results channel, rolling progress ticker, final summary) mirrors a
+// typical Go load-test tool.
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	var (
+		n           = fs.Int("n", 10000, "number of processes to generate")
+		seed        = fs.Int64("seed", 1, "random seed for the workload generator")
+		arrivalDist = fs.String("arrival", "poisson", "arrival time distribution (currently only: poisson)")
+		lambda      = fs.Float64("lambda", 1.0, "arrival rate for -arrival=poisson")
+		burstDist   = fs.String("burst", "exp", "burst duration distribution (currently only: exp)")
+		mean        = fs.Float64("mean", 5.0, "mean burst duration for -burst=exp")
+		cpuprofile  = fs.String("cpuprofile", "", "write a CPU profile to this file")
+		memprofile  = fs.String("memprofile", "", "write a memory profile to this file")
+		csvout      = fs.String("csvout", "", "dump the generated workload to this CSV file for reproducibility")
+		statusAddr  = fs.String("status-addr", "", "serve live progress snapshots on this address (e.g. localhost:8080), empty disables it")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *cpuprofile != "" {
+		f, err := os.Create(*cpuprofile)
+		if err != nil {
+			return fmt.Errorf("%w: creating -cpuprofile", err)
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return fmt.Errorf("%w: starting cpu profile", err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	processes := generateWorkload(*n, *seed, *arrivalDist, *lambda, *burstDist, *mean)
+
+	if *csvout != "" {
+		if err := writeWorkloadCSV(*csvout, processes); err != nil {
+			return fmt.Errorf("%w: writing -csvout", err)
+		}
+	}
+
+	runs := make([]benchRun, 0, len(schedulers))
+	for name, s := range schedulers {
+		runs = append(runs, benchRun{name: name, scheduler: s})
+	}
+
+	var hb *heartbeat
+	if *statusAddr != "" {
+		hb = newHeartbeat("bench", int64(len(runs)))
+		statusDone := make(chan struct{})
+		defer close(statusDone)
+		go hb.run(statusDone, 2*time.Second)
+		go func() {
+			if err := serveStatus(*statusAddr, hb, statusDone); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "status server: %v\n", err)
+			}
+		}()
+	}
+
+	results := runBenchAll(runs, processes, hb)
+	measureAllocs(runs, processes, results)
+
+	sort.Slice(results, func(i, j int) bool { return results[i].name < results[j].name })
+	outputBenchTable(os.Stdout, results)
+
+	if *memprofile != "" {
+		f, err := os.Create(*memprofile)
+		if err != nil {
+			return fmt.Errorf("%w: creating -memprofile", err)
+		}
+		defer f.Close()
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			return fmt.Errorf("%w: writing mem profile", err)
+		}
+	}
+
+	return nil
+}
+
+// runBenchAll runs every scheduler concurrently, printing a rolling
+// "N/total done" line to stderr every couple of seconds so a user waiting
+// on a large -n doesn't stare at a blank terminal. If hb is non-nil (i.e.
+// -status-addr was set), the same completed count feeds its Snapshot so
+// `scheduler ps` sees the same progress; since Scheduler.Schedule runs a
+// pass to completion with no incremental hook, "completed"/"remaining" in
+// bench mode count schedulers finished out of len(runs), not processes.
+func runBenchAll(runs []benchRun, processes []Process, hb *heartbeat) []benchResult {
+	results := make(chan benchResult, len(runs))
+	var completed int64
+	var wg sync.WaitGroup
+
+	for _, r := range runs {
+		wg.Add(1)
+		go func(r benchRun) {
+			defer wg.Done()
+			results <- runBenchOne(r.name, r.scheduler, processes)
+			atomic.AddInt64(&completed, 1)
+			if hb != nil {
+				hb.addCompleted(1)
+			}
+		}(r)
+	}
+
+	progressDone := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(2 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				_, _ = fmt.Fprintf(os.Stderr, "bench: %d/%d schedulers done\n", atomic.LoadInt64(&completed), len(runs))
+			case <-progressDone:
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	all := make([]benchResult, 0, len(runs))
+	for res := range results {
+		all = append(all, res)
+	}
+	close(progressDone)
+
+	return all
+}
+
+// runBenchOne times a single scheduler's Schedule call, so hot paths can be
+// spotted without attaching a profiler. p99 wait is derived from the same
+// digest outputResult uses for the gantt/table path, so bench and the normal
+// CLI output agree. Allocations aren't measured here: runBenchAll runs every
+// scheduler concurrently, and runtime.MemStats counters are process-global,
+// so a per-goroutine before/after delta would also count whatever the other
+// schedulers allocated in the meantime. See measureAllocs.
+func runBenchOne(name string, s Scheduler, processes []Process) benchResult {
+	input := make([]Process, len(processes))
+	copy(input, processes)
+
+	start := time.Now()
+	result := s.Schedule(input, nil)
+	elapsed := time.Since(start)
+
+	return benchResult{
+		name:       name,
+		avgWait:    result.AvgWait,
+		avgTurn:    result.AvgTurnaround,
+		throughput: result.Throughput,
+		p99Wait:    result.WaitDigest.Quantile(0.99),
+		wallClock:  elapsed,
+	}
+}
+
+// measureAllocs fills in each result's allocs by re-running its scheduler
+// alone, one at a time, so runtime.ReadMemStats' process-global counters
+// only see that scheduler's own allocations. It's a second, serial pass
+// over the same input rather than folding into runBenchAll's concurrent
+// one, trading extra wall-clock time for a trustworthy number.
+func measureAllocs(runs []benchRun, processes []Process, results []benchResult) {
+	byName := make(map[string]*benchResult, len(results))
+	for i := range results {
+		byName[results[i].name] = &results[i]
+	}
+
+	input := make([]Process, len(processes))
+	for _, r := range runs {
+		copy(input, processes)
+
+		runtime.GC()
+		var before runtime.MemStats
+		runtime.ReadMemStats(&before)
+
+		r.scheduler.Schedule(input, nil)
+
+		var after runtime.MemStats
+		runtime.ReadMemStats(&after)
+
+		if res, ok := byName[r.name]; ok {
+			res.allocs = after.Mallocs - before.Mallocs
+		}
+	}
+}
+
+func outputBenchTable(w io.Writer, results []benchResult) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Scheduler", "Avg Wait", "Avg Turnaround", "Throughput", "p99 Wait", "Wall Clock", "Allocs"})
+
+	var fastest, slowest benchResult
+	for i, r := range results {
+		table.Append([]string{
+			r.name,
+			fmt.Sprintf("%.2f", r.avgWait),
+			fmt.Sprintf("%.2f", r.avgTurn),
+			fmt.Sprintf("%.2f/t", r.throughput),
+			fmt.Sprintf("%.2f", r.p99Wait),
+			r.wallClock.String(),
+			fmt.Sprint(r.allocs),
+		})
+		if i == 0 || r.wallClock < fastest.wallClock {
+			fastest = r
+		}
+		if i == 0 || r.wallClock > slowest.wallClock {
+			slowest = r
+		}
+	}
+	table.Render()
+
+	_, _ = fmt.Fprintf(w, "Fastest: %s (%s)   Slowest: %s (%s)\n", fastest.name, fastest.wallClock, slowest.name, slowest.wallClock)
+}
+
+//region Workload generation
+
+// generateWorkload synthesizes n processes without needing a CSV file. A
+// pool of goroutines draws the random samples concurrently so large -n
+// values don't serialize on a single core; each worker is pinned to a fixed
+// set of indices (i % workers) and its own seeded RNG, rather than pulling
+// whichever index is next off a shared channel, so which stream a given
+// sample comes from - and therefore the output for a given -seed - doesn't
+// depend on goroutine scheduling. The arrival times are then recovered from
+// the per-process inter-arrival gaps with a sequential prefix sum, since a
+// Poisson process is exactly a running sum of i.i.d. exponential gaps.
+func generateWorkload(n int, seed int64, arrivalDist string, lambda float64, burstDist string, mean float64) []Process {
+	type sample struct {
+		gap   float64
+		burst float64
+	}
+
+	samples := make([]sample, n)
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int, workerSeed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(workerSeed))
+			for i := w; i < n; i += workers {
+				samples[i] = sample{
+					gap:   sampleArrivalGap(rng, arrivalDist, lambda),
+					burst: sampleBurst(rng, burstDist, mean),
+				}
+			}
+		}(w, seed+int64(w)+1)
+	}
+	wg.Wait()
+
+	processes := make([]Process, n)
+	var clock float64
+	for i, s := range samples {
+		clock += s.gap
+		processes[i] = Process{
+			ProcessID:     int64(i + 1),
+			ArrivalTime:   int64(clock),
+			BurstDuration: int64(math.Max(1, s.burst)),
+			Priority:      int64(1 + i%5),
+		}
+	}
+
+	return processes
+}
+
+func sampleArrivalGap(rng *rand.Rand, dist string, lambda float64) float64 {
+	switch dist {
+	case "poisson":
+		return rng.ExpFloat64() / lambda
+	default:
+		return rng.ExpFloat64() / lambda
+	}
+}
+
+func sampleBurst(rng *rand.Rand, dist string, mean float64) float64 {
+	switch dist {
+	case "exp":
+		return rng.ExpFloat64() * mean
+	default:
+		return rng.ExpFloat64() * mean
+	}
+}
+
+func writeWorkloadCSV(path string, processes []Process) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cw := csv.NewWriter(f)
+	for _, p := range processes {
+		if err := cw.Write([]string{
+			strconv.FormatInt(p.ProcessID, 10),
+			strconv.FormatInt(p.BurstDuration, 10),
+			strconv.FormatInt(p.ArrivalTime, 10),
+			strconv.FormatInt(p.Priority, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+//endregion
@@ -0,0 +1,176 @@
+// Package tdigest implements a streaming approximation of a distribution's
+// CDF using weighted centroids, as described in Ted Dunning's t-digest
+// paper. It lets callers compute quantiles (p50, p99, ...) from a stream of
+// samples without buffering or sorting the full sample set, which matters
+// once a simulation produces millions of wait/turnaround samples.
+package tdigest
+
+import (
+	"math"
+	"sort"
+)
+
+// defaultCompression (δ) scales the per-centroid weight bound in sizeBound:
+// a higher value lets each centroid absorb more samples, so fewer centroids
+// are kept. The bound does not grow with the total weight, so the number of
+// centroids grows with the number of samples (a uniform stream of n values
+// ends up with roughly n/16 centroids at δ=100).
+const defaultCompression = 100
+
+// centroid is a single weighted mean tracked by a Digest.
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// Digest is a t-digest: an ordered collection of weighted centroids that
+// approximates the empirical CDF of a stream of samples. The zero value is
+// not usable; construct one with New.
+type Digest struct {
+	compression float64
+	centroids   []centroid
+	totalWeight float64
+	unmerged    int
+}
+
+// New returns an empty Digest using the default compression of 100.
+func New() *Digest {
+	return &Digest{compression: defaultCompression}
+}
+
+// Add records a sample x with weight w into the digest. Most callers pass
+// w=1 for a single observation.
+func (d *Digest) Add(x, w float64) {
+	if w <= 0 {
+		return
+	}
+	if len(d.centroids) == 0 {
+		d.centroids = []centroid{{mean: x, weight: w}}
+		d.totalWeight = w
+		return
+	}
+
+	i := d.nearest(x)
+	if i >= 0 && d.centroids[i].weight+w <= d.sizeBound(i) {
+		c := &d.centroids[i]
+		c.mean += (x - c.mean) * w / (c.weight + w)
+		c.weight += w
+	} else {
+		// Insert at the sorted position rather than appending at the
+		// tail: nearest's binary search and Quantile's rank walk both
+		// assume centroids stay sorted by mean between compressions,
+		// and unmerged inserts can outnumber a compress for a while.
+		at := sort.Search(len(d.centroids), func(j int) bool {
+			return d.centroids[j].mean >= x
+		})
+		d.centroids = append(d.centroids, centroid{})
+		copy(d.centroids[at+1:], d.centroids[at:])
+		d.centroids[at] = centroid{mean: x, weight: w}
+		d.unmerged++
+	}
+	d.totalWeight += w
+
+	if d.unmerged > len(d.centroids)/2+1 {
+		d.compress()
+	}
+}
+
+// nearest returns the index of the centroid whose mean is closest to x.
+// Centroids stay sorted by mean between compressions, so a binary search
+// narrows to the insertion point first.
+func (d *Digest) nearest(x float64) int {
+	i := sort.Search(len(d.centroids), func(i int) bool {
+		return d.centroids[i].mean >= x
+	})
+
+	best, bestDist := -1, math.Inf(1)
+	for _, j := range [2]int{i - 1, i} {
+		if j < 0 || j >= len(d.centroids) {
+			continue
+		}
+		if dist := math.Abs(d.centroids[j].mean - x); dist < bestDist {
+			best, bestDist = j, dist
+		}
+	}
+	return best
+}
+
+// sizeBound returns the maximum combined weight centroid i may absorb
+// before it must split instead of merge, following
+// k(q, δ) = (δ/2π) * (asin(2q-1) + π/2), where q is the fraction of total
+// weight that falls strictly before centroid i. The bound rises from 0 at
+// the low end (q=0) through δ/4 at the median to δ/2 at the high end, so
+// centroids at the low tail stay small and those at the high tail are the
+// coarsest. It does not depend on the total weight.
+func (d *Digest) sizeBound(i int) float64 {
+	var cumBefore float64
+	for j := 0; j < i; j++ {
+		cumBefore += d.centroids[j].weight
+	}
+	q := cumBefore / d.totalWeight
+	return d.compression / (2 * math.Pi) * (math.Asin(2*q-1) + math.Pi/2)
+}
+
+// compress re-sorts the centroids by mean and greedily re-merges
+// neighbors under the same size bound, bringing the digest back down near
+// its target centroid count. Unlike Dunning's single-pass streaming
+// construction, this always re-merges from a full sort rather than
+// incremental insertion order, so there's no ordering bias to shuffle
+// away — and skipping the shuffle keeps compress, and therefore Quantile,
+// deterministic for a given sequence of Adds.
+func (d *Digest) compress() {
+	sort.Slice(d.centroids, func(i, j int) bool {
+		return d.centroids[i].mean < d.centroids[j].mean
+	})
+
+	merged := d.centroids[:0]
+	var cumWeight float64
+	for _, c := range d.centroids {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			q := cumWeight / d.totalWeight
+			bound := d.compression / (2 * math.Pi) * (math.Asin(2*q-1) + math.Pi/2)
+			if last.weight+c.weight <= bound {
+				last.mean += (c.mean - last.mean) * c.weight / (last.weight + c.weight)
+				last.weight += c.weight
+				cumWeight += c.weight
+				continue
+			}
+		}
+		merged = append(merged, c)
+		cumWeight += c.weight
+	}
+	d.centroids = merged
+	d.unmerged = 0
+}
+
+// Quantile estimates the value at rank q (0 ≤ q ≤ 1). Each centroid's mean
+// is taken to sit at rank (weight before it) + (its own weight)/2 - the
+// standard t-digest convention - and the estimate linearly interpolates
+// between the two centroid (rank, mean) points that bracket the target
+// rank.
+func (d *Digest) Quantile(q float64) float64 {
+	switch len(d.centroids) {
+	case 0:
+		return 0
+	case 1:
+		return d.centroids[0].mean
+	}
+	q = math.Min(math.Max(q, 0), 1)
+	target := q * d.totalWeight
+
+	var cumBefore, prevRank, prevMean float64
+	for i, c := range d.centroids {
+		rank := cumBefore + c.weight/2
+		if target <= rank {
+			if i == 0 {
+				return c.mean
+			}
+			frac := (target - prevRank) / (rank - prevRank)
+			return prevMean + frac*(c.mean-prevMean)
+		}
+		cumBefore += c.weight
+		prevRank, prevMean = rank, c.mean
+	}
+	return d.centroids[len(d.centroids)-1].mean
+}
@@ -0,0 +1,54 @@
+package tdigest
+
+import "testing"
+
+// TestQuantileMonotonic guards against the centroids slice going unsorted
+// between compressions (the bug that let Quantile(0.9) come back lower than
+// Quantile(0.5)): insert a tiny multiset out of order and check quantiles
+// only increase as q increases.
+func TestQuantileMonotonic(t *testing.T) {
+	d := New()
+	for _, x := range []float64{5, 3, 10, 4} {
+		d.Add(x, 1)
+	}
+
+	prev := d.Quantile(0)
+	for _, q := range []float64{0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 1} {
+		got := d.Quantile(q)
+		if got < prev {
+			t.Fatalf("Quantile(%v) = %v, want >= Quantile of smaller q (%v)", q, got, prev)
+		}
+		prev = got
+	}
+}
+
+// TestQuantileAccuracy checks a digest fed a known uniform stream reports
+// quantiles reasonably close to the true ones, catching gross errors like
+// the unsorted-centroid bug (which put p99 at roughly half the true value).
+func TestQuantileAccuracy(t *testing.T) {
+	d := New()
+	const n = 5000
+	for i := 0; i < n; i++ {
+		// Alternate insertion order so values don't arrive pre-sorted.
+		x := i
+		if i%2 == 1 {
+			x = n - i
+		}
+		d.Add(float64(x), 1)
+	}
+
+	cases := []struct {
+		q    float64
+		want float64
+	}{
+		{0.5, n / 2},
+		{0.9, n * 0.9},
+		{0.99, n * 0.99},
+	}
+	for _, c := range cases {
+		got := d.Quantile(c.q)
+		if tol := n * 0.02; got < c.want-tol || got > c.want+tol {
+			t.Errorf("Quantile(%v) = %v, want within %v of %v", c.q, got, tol, c.want)
+		}
+	}
+}
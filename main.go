@@ -1,21 +1,63 @@
 package main
 
 import (
+	"container/heap"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
+
+	"github.com/Obstruct1/Project1/internal/tdigest"
 )
 
+// format selects how a schedule's timeline is rendered: "gantt" for the
+// classic ASCII bar (one table per scheduler), "trace" for a single Chrome
+// Trace Event Format JSON document covering every scheduler, with no
+// other output.
+var format string
+
+// cpus is the number of simulated CPU cores processes are dispatched
+// across. 1 (the default) keeps the original single-CPU behavior.
+var cpus int
+
+// statusAddr, when non-empty, serves a live progress Snapshot as JSON at
+// /status so a `scheduler ps` (or any scriptable client) can poll it
+// without waiting for the run to finish.
+var statusAddr string
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "bench":
+			if err := runBench(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "ps":
+			if err := runPS(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	flag.StringVar(&format, "format", "gantt", "output format for the schedule timeline: gantt (ASCII, per scheduler) or trace (one Chrome Trace Event JSON document for every scheduler)")
+	flag.IntVar(&cpus, "cpus", 1, "number of simulated CPU cores to dispatch processes across")
+	flag.StringVar(&statusAddr, "status-addr", "", "serve live progress snapshots on this address (e.g. localhost:8080), empty disables it")
+	flag.Parse()
+
 	// CLI args
-	f, closeFile, err := openProcessingFile(os.Args...)
+	f, closeFile, err := openProcessingFile(os.Args[0], flag.Arg(0))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -27,18 +69,44 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// First-come, first-serve scheduling
-	FCFSSchedule(os.Stdout, "First-come, first-serve", processes)
+	if format == "trace" {
+		if err := outputTraceFile(os.Stdout, processes, cpus); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var hb *heartbeat
+	if statusAddr != "" {
+		hb = newHeartbeat(schedulerOrder[0], int64(len(processes)*len(schedulerOrder)))
+		statusDone := make(chan struct{})
+		defer close(statusDone)
+		go hb.run(statusDone, 2*time.Second)
+		go func() {
+			if err := serveStatus(statusAddr, hb, statusDone); err != nil {
+				log.Printf("status server: %v", err)
+			}
+		}()
+	}
 
-	SJFSchedule(os.Stdout, "Shortest-job-first", processes)
-	//
-	//SJFPrioritySchedule(os.Stdout, "Priority", processes)
-	//
-	//RRSchedule(os.Stdout, "Round-robin", processes)
+	for _, name := range schedulerOrder {
+		if hb != nil {
+			hb.setScheduler(name)
+		}
+		if cpus > 1 {
+			outputMultiCPUResult(os.Stdout, schedulers[name].Name(), runMultiCPU(name, processes, cpus))
+		} else {
+			result := schedulers[name].Schedule(processes, hb)
+			outputResult(os.Stdout, schedulers[name].Name(), result)
+		}
+		if hb != nil {
+			hb.addCompleted(int64(len(processes)))
+		}
+	}
 }
 
 func openProcessingFile(args ...string) (*os.File, func(), error) {
-	if len(args) != 2 {
+	if len(args) != 2 || args[1] == "" {
 		return nil, nil, fmt.Errorf("%w: must give a scheduling file to process", ErrInvalidArgs)
 	}
 	// Read in CSV process CSV file
@@ -71,227 +139,515 @@ type (
 
 //region Schedulers
 
-// FCFSSchedule outputs a schedule of processes in a GANTT chart and a table of timing given:
-// • an output writer
-// • a title for the chart
-// • a slice of processes
-func FCFSSchedule(w io.Writer, title string, processes []Process) {
+// Scheduler computes a schedule for a batch of processes. FCFS, the SJF
+// family, priority, and round robin all implement it so main (and the
+// bench subcommand) can run any of them uniformly instead of hardcoding a
+// call per algorithm. hb receives live clock/ready-depth/running-PID
+// progress as Schedule runs; pass nil to skip that (bench does, since its
+// concurrent schedulers would otherwise clobber one shared heartbeat's
+// state).
+type Scheduler interface {
+	Name() string
+	Schedule(processes []Process, hb *heartbeat) Result
+}
+
+// Result is everything a Scheduler produces: the rendered schedule rows,
+// the Gantt timeline, the three headline averages shown in the table's
+// footer, and the wait/turnaround t-digests built up alongside them
+// during the same pass, so tail latencies don't need a second walk over
+// every sample.
+type Result struct {
+	Rows             [][]string
+	Gantt            []TimeSlice
+	AvgWait          float64
+	AvgTurnaround    float64
+	Throughput       float64
+	WaitDigest       *tdigest.Digest
+	TurnaroundDigest *tdigest.Digest
+}
+
+// schedulerOrder fixes the iteration order over schedulers so CLI output
+// is deterministic; map iteration order in Go is not.
+var schedulerOrder = []string{"FCFS", "SJF", "SRTF", "Priority", "RR"}
+
+// schedulers is every Scheduler main and the bench subcommand can run,
+// keyed by name so neither has to hardcode the list of algorithms.
+var schedulers = map[string]Scheduler{
+	"FCFS":     FCFS{},
+	"SJF":      SJFNonPreemptive{},
+	"SRTF":     SJFPreemptive{},
+	"Priority": PriorityPreemptive{},
+	"RR":       RoundRobin{Quantum: 2},
+}
+
+// FCFS is first-come, first-serve: processes run in arrival order with no
+// preemption.
+type FCFS struct{}
+
+func (FCFS) Name() string { return "First-come, first-serve" }
+
+func (FCFS) Schedule(processes []Process, hb *heartbeat) Result {
+	sorted := make([]Process, len(processes))
+	copy(sorted, processes)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ArrivalTime < sorted[j].ArrivalTime })
+
 	var (
-		serviceTime     int64
-		totalWait       float64
-		turnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
+		clock, totalWait, totalTurnaround int64
+		rows                              = make([][]string, len(sorted))
+		gantt                             = make([]TimeSlice, 0, len(sorted))
+		waitDigest, turnaroundDigest      = tdigest.New(), tdigest.New()
 	)
-	for i := range processes {
-		if processes[i].ArrivalTime > 0 {
-			waitingTime = serviceTime - processes[i].ArrivalTime
+
+	for i := range sorted {
+		var wait int64
+		if sorted[i].ArrivalTime > clock {
+			clock = sorted[i].ArrivalTime
+		} else {
+			wait = clock - sorted[i].ArrivalTime
 		}
-		totalWait += float64(waitingTime)
 
-		start := waitingTime + processes[i].ArrivalTime
+		start := clock
+		clock += sorted[i].BurstDuration
+		turnaround := wait + sorted[i].BurstDuration
 
-		turnaround := processes[i].BurstDuration + waitingTime
-		turnaround += float64(turnaround)
+		totalWait += wait
+		totalTurnaround += turnaround
+		waitDigest.Add(float64(wait), 1)
+		turnaroundDigest.Add(float64(turnaround), 1)
 
-		completion := processes[i].BurstDuration + processes[i].ArrivalTime + waitingTime
-		lastCompletion = float64(completion)
+		rows[i] = processRow(sorted[i], wait, turnaround, clock)
+		gantt = append(gantt, TimeSlice{PID: sorted[i].ProcessID, Start: start, Stop: clock})
 
-		schedule[i] = []string{
-			fmt.Sprint(processes[i].ProcessID),
-			fmt.Sprint(processes[i].Priority),
-			fmt.Sprint(processes[i].BurstDuration),
-			fmt.Sprint(processes[i].ArrivalTime),
-			fmt.Sprint(waitingTime),
-			fmt.Sprint(turnaround),
-			fmt.Sprint(completion),
+		ready := 0
+		for j := i + 1; j < len(sorted) && sorted[j].ArrivalTime <= clock; j++ {
+			ready++
 		}
-		serviceTime += processes[i].BurstDuration
+		hb.tick(clock, ready, sorted[i].ProcessID)
+	}
 
-		gantt = append(gantt, TimeSlice{
-			PID:   processes[i].ProcessID,
-			Start: start,
-			Stop:  serviceTime,
-		})
+	count := float64(len(sorted))
+	return Result{
+		Rows:             rows,
+		Gantt:            gantt,
+		AvgWait:          float64(totalWait) / count,
+		AvgTurnaround:    float64(totalTurnaround) / count,
+		Throughput:       count / float64(clock),
+		WaitDigest:       waitDigest,
+		TurnaroundDigest: turnaroundDigest,
 	}
+}
 
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := turnaround / count
-	aveThroughput := count / lastCompletion
+// SJFNonPreemptive runs the shortest available burst to completion before
+// picking the next one.
+type SJFNonPreemptive struct{}
 
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
-}
+func (SJFNonPreemptive) Name() string { return "Shortest-job-first" }
 
-func SJFSchedule(w io.Writer, title string, processes []Process) {
-	// Sort processes by arrival time initially
-	sort.Slice(processes, func(i, j int) bool {
-		return processes[i].ArrivalTime < processes[j].ArrivalTime
-	})
+func (SJFNonPreemptive) Schedule(processes []Process, hb *heartbeat) Result {
+	sorted := make([]Process, len(processes))
+	copy(sorted, processes)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ArrivalTime < sorted[j].ArrivalTime })
+
+	h := &burstHeap{}
+	heap.Init(h)
 
 	var (
-		currentTime     int64
-		turnaround int64
-		totalWait       int64
-		completed       int
+		clock, totalWait, totalTurnaround int64
+		rows                              = make([][]string, 0, len(sorted))
+		gantt                             = make([]TimeSlice, 0, len(sorted))
+		waitDigest, turnaroundDigest      = tdigest.New(), tdigest.New()
+		next                              int
 	)
 
-	// Implementing a min heap to always get the process with the shortest burst time
-	h := &IntHeap{}
+	for h.Len() > 0 || next < len(sorted) {
+		for next < len(sorted) && sorted[next].ArrivalTime <= clock {
+			heap.Push(h, sorted[next])
+			next++
+		}
+		if h.Len() == 0 {
+			clock = sorted[next].ArrivalTime
+			continue
+		}
+
+		p := heap.Pop(h).(Process)
+		start := clock
+		clock += p.BurstDuration
+		wait := start - p.ArrivalTime
+		turnaround := wait + p.BurstDuration
+
+		totalWait += wait
+		totalTurnaround += turnaround
+		waitDigest.Add(float64(wait), 1)
+		turnaroundDigest.Add(float64(turnaround), 1)
+
+		rows = append(rows, processRow(p, wait, turnaround, clock))
+		gantt = append(gantt, TimeSlice{PID: p.ProcessID, Start: start, Stop: clock})
+		hb.tick(clock, h.Len(), p.ProcessID)
+	}
+
+	count := float64(len(sorted))
+	return Result{
+		Rows:             rows,
+		Gantt:            gantt,
+		AvgWait:          float64(totalWait) / count,
+		AvgTurnaround:    float64(totalTurnaround) / count,
+		Throughput:       count / float64(clock),
+		WaitDigest:       waitDigest,
+		TurnaroundDigest: turnaroundDigest,
+	}
+}
+
+// burstHeap is a min-heap of processes ordered by burst duration, ties
+// broken by arrival time. SJFNonPreemptive uses it to pick the shortest
+// job among those that have already arrived.
+type burstHeap []Process
+
+func (h burstHeap) Len() int { return len(h) }
+func (h burstHeap) Less(i, j int) bool {
+	if h[i].BurstDuration != h[j].BurstDuration {
+		return h[i].BurstDuration < h[j].BurstDuration
+	}
+	return h[i].ArrivalTime < h[j].ArrivalTime
+}
+func (h burstHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *burstHeap) Push(x any) { *h = append(*h, x.(Process)) }
+
+func (h *burstHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// SJFPreemptive is shortest-remaining-time-first: whichever arrived
+// process has the least remaining burst runs next, preempting the
+// currently running one if a shorter job arrives.
+type SJFPreemptive struct{}
+
+func (SJFPreemptive) Name() string { return "Shortest-remaining-time-first" }
+
+func (SJFPreemptive) Schedule(processes []Process, hb *heartbeat) Result {
+	sorted := make([]Process, len(processes))
+	copy(sorted, processes)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ArrivalTime < sorted[j].ArrivalTime })
+
+	n := len(sorted)
+	remaining := make([]int64, n)
+	for i := range sorted {
+		remaining[i] = sorted[i].BurstDuration
+	}
+
+	h := &remainingBurstHeap{remaining: remaining, arrival: make([]int64, n)}
+	for i := range sorted {
+		h.arrival[i] = sorted[i].ArrivalTime
+	}
 	heap.Init(h)
 
-	for completed < len(processes) || h.Len() > 0 {
-		for _, p := range processes {
-			if p.ArrivalTime <= currentTime && p.BurstDuration > 0 {
-				heap.Push(h, p)
-			}
+	var (
+		clock, completed       int64
+		waitOf, turnaroundOf   = make([]int64, n), make([]int64, n)
+		gantt                  []TimeSlice
+		running                = -1
+		sliceStart             int64
+		next                   int
+	)
+
+	for completed < int64(n) {
+		for next < n && sorted[next].ArrivalTime <= clock {
+			heap.Push(h, next)
+			next++
+		}
+		if h.Len() == 0 {
+			clock = sorted[next].ArrivalTime
+			continue
 		}
 
-		if h.Len() > 0 {
-			p := heap.Pop(h).(Process)
-			p.BurstDuration--
-			currentTime++
-
-			if p.BurstDuration == 0 {
-				completed++
-				turnaround += currentTime - p.ArrivalTime
-				totalWait += currentTime - p.ArrivalTime - p.BurstDuration
-			} else {
-				heap.Push(h, p)
+		idx := heap.Pop(h).(int)
+		if idx != running {
+			if running != -1 {
+				gantt = append(gantt, TimeSlice{PID: sorted[running].ProcessID, Start: sliceStart, Stop: clock})
 			}
+			running, sliceStart = idx, clock
+		}
+
+		remaining[idx]--
+		clock++
+		hb.tick(clock, h.Len(), sorted[idx].ProcessID)
+
+		if remaining[idx] == 0 {
+			completed++
+			turnaroundOf[idx] = clock - sorted[idx].ArrivalTime
+			waitOf[idx] = turnaroundOf[idx] - sorted[idx].BurstDuration
+			gantt = append(gantt, TimeSlice{PID: sorted[idx].ProcessID, Start: sliceStart, Stop: clock})
+			running = -1
 		} else {
-			currentTime++
+			heap.Push(h, idx)
 		}
 	}
 
-	// Calculating averages
-	avgTurnaround := float64(turnaround) / float64(len(processes))
-	avgWait := float64(totalWait) / float64(len(processes))
-	throughput := float64(len(processes)) / float64(currentTime)
+	return buildPreemptiveResult(sorted, waitOf, turnaroundOf, gantt, clock)
+}
+
+// remainingBurstHeap is a min-heap of process indices ordered by remaining
+// burst, ties broken by arrival time, as required for shortest-remaining-
+// time-first.
+type remainingBurstHeap struct {
+	idx       []int
+	remaining []int64
+	arrival   []int64
+}
+
+func (h remainingBurstHeap) Len() int { return len(h.idx) }
+func (h remainingBurstHeap) Less(i, j int) bool {
+	pi, pj := h.idx[i], h.idx[j]
+	if h.remaining[pi] != h.remaining[pj] {
+		return h.remaining[pi] < h.remaining[pj]
+	}
+	return h.arrival[pi] < h.arrival[pj]
+}
+func (h remainingBurstHeap) Swap(i, j int) { h.idx[i], h.idx[j] = h.idx[j], h.idx[i] }
+
+func (h *remainingBurstHeap) Push(x any) { h.idx = append(h.idx, x.(int)) }
 
-	// Output the results
-	fmt.Printf("Average Turnaround Time: %.2f\n", avgTurnaround)
-	fmt.Printf("Average Waiting Time: %.2f\n", avgWait)
-	fmt.Printf("Throughput: %.2f\n", throughput)
+func (h *remainingBurstHeap) Pop() any {
+	old := h.idx
+	n := len(old)
+	item := old[n-1]
+	h.idx = old[:n-1]
+	return item
 }
-//
-func SJFSchedule(w io.Writer, title string, processes []Process) {
+
+// PriorityPreemptive runs whichever arrived process has the numerically
+// lowest Priority, preempting the currently running one if a
+// higher-priority process arrives.
+type PriorityPreemptive struct{}
+
+func (PriorityPreemptive) Name() string { return "Priority" }
+
+func (PriorityPreemptive) Schedule(processes []Process, hb *heartbeat) Result {
+	sorted := make([]Process, len(processes))
+	copy(sorted, processes)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ArrivalTime < sorted[j].ArrivalTime })
+
+	n := len(sorted)
+	remaining := make([]int64, n)
+	priority := make([]int64, n)
+	arrival := make([]int64, n)
+	for i := range sorted {
+		remaining[i] = sorted[i].BurstDuration
+		priority[i] = sorted[i].Priority
+		arrival[i] = sorted[i].ArrivalTime
+	}
+
+	h := &priorityHeap{priority: priority, arrival: arrival}
+	heap.Init(h)
+
 	var (
-		totalWait       float64
-		totalTurnaround float64
-		schedule        = make([][]string, 0)
-		gantt           = make([]TimeSlice, 0)
-		currentTime     int64
+		clock, completed     int64
+		waitOf, turnaroundOf = make([]int64, n), make([]int64, n)
+		gantt                []TimeSlice
+		running              = -1
+		sliceStart           int64
+		next                 int
 	)
 
-	// Create a priority queue with a custom Less function to consider both priority and burst time
-	h := &PriorityHeap{}
-	heap.Init(h)
+	for completed < int64(n) {
+		for next < n && sorted[next].ArrivalTime <= clock {
+			heap.Push(h, next)
+			next++
+		}
+		if h.Len() == 0 {
+			clock = sorted[next].ArrivalTime
+			continue
+		}
 
-	for len(*h) > 0 || len(processes) > 0 {
-		for i := 0; i < len(processes); {
-			if processes[i].ArrivalTime <= currentTime && processes[i].BurstDuration > 0 {
-				heap.Push(h, processes[i])
-				processes = append(processes[:i], processes[i+1:]...)
-				continue
+		idx := heap.Pop(h).(int)
+		if idx != running {
+			if running != -1 {
+				gantt = append(gantt, TimeSlice{PID: sorted[running].ProcessID, Start: sliceStart, Stop: clock})
 			}
-			i++
+			running, sliceStart = idx, clock
 		}
 
-		if h.Len() > 0 {
-			p := heap.Pop(h).(Process)
-			startTime := currentTime
-			currentTime += p.BurstDuration
-			waitTime := startTime - p.ArrivalTime
-			turnaroundTime := currentTime - p.ArrivalTime
-
-			totalWait += float64(waitTime)
-			totalTurnaround += float64(turnaroundTime)
-
-			schedule = append(schedule, []string{
-				fmt.Sprint(p.ProcessID),
-				fmt.Sprint(p.Priority),
-				fmt.Sprint(p.BurstDuration + waitTime), // original burst time
-				fmt.Sprint(p.ArrivalTime),
-				fmt.Sprint(waitTime),
-				fmt.Sprint(turnaroundTime),
-				fmt.Sprint(currentTime),
-			})
-
-			gantt = append(gantt, TimeSlice{
-				PID:   p.ProcessID,
-				Start: startTime,
-				Stop:  currentTime,
-			})
+		remaining[idx]--
+		clock++
+		hb.tick(clock, h.Len(), sorted[idx].ProcessID)
+
+		if remaining[idx] == 0 {
+			completed++
+			turnaroundOf[idx] = clock - sorted[idx].ArrivalTime
+			waitOf[idx] = turnaroundOf[idx] - sorted[idx].BurstDuration
+			gantt = append(gantt, TimeSlice{PID: sorted[idx].ProcessID, Start: sliceStart, Stop: clock})
+			running = -1
 		} else {
-			currentTime++
+			heap.Push(h, idx)
 		}
 	}
 
-	// Calculating averages
-	avgWait := totalWait / float64(len(schedule))
-	avgTurnaround := totalTurnaround / float64(len(schedule))
-	throughput := float64(len(schedule)) / float64(currentTime)
+	return buildPreemptiveResult(sorted, waitOf, turnaroundOf, gantt, clock)
+}
 
-	// Output the results in a similar format to your original FCFSSchedule function
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, avgWait, avgTurnaround, throughput)
+// priorityHeap is a min-heap of process indices ordered by Priority (lower
+// numeric value preempts), ties broken by arrival time.
+type priorityHeap struct {
+	idx      []int
+	priority []int64
+	arrival  []int64
+}
+
+func (h priorityHeap) Len() int { return len(h.idx) }
+func (h priorityHeap) Less(i, j int) bool {
+	pi, pj := h.idx[i], h.idx[j]
+	if h.priority[pi] != h.priority[pj] {
+		return h.priority[pi] < h.priority[pj]
+	}
+	return h.arrival[pi] < h.arrival[pj]
+}
+func (h priorityHeap) Swap(i, j int) { h.idx[i], h.idx[j] = h.idx[j], h.idx[i] }
+
+func (h *priorityHeap) Push(x any) { h.idx = append(h.idx, x.(int)) }
+
+func (h *priorityHeap) Pop() any {
+	old := h.idx
+	n := len(old)
+	item := old[n-1]
+	h.idx = old[:n-1]
+	return item
+}
+
+// buildPreemptiveResult assembles a Result from the per-process wait and
+// turnaround times recorded during a preemptive simulation, shared by
+// SJFPreemptive and PriorityPreemptive. It feeds the t-digests from the
+// same pass that builds the rows, rather than re-parsing them afterward.
+func buildPreemptiveResult(sorted []Process, waitOf, turnaroundOf []int64, gantt []TimeSlice, clock int64) Result {
+	var totalWait, totalTurnaround int64
+	rows := make([][]string, len(sorted))
+	waitDigest, turnaroundDigest := tdigest.New(), tdigest.New()
+	for i := range sorted {
+		totalWait += waitOf[i]
+		totalTurnaround += turnaroundOf[i]
+		waitDigest.Add(float64(waitOf[i]), 1)
+		turnaroundDigest.Add(float64(turnaroundOf[i]), 1)
+		rows[i] = processRow(sorted[i], waitOf[i], turnaroundOf[i], sorted[i].ArrivalTime+turnaroundOf[i])
+	}
+
+	count := float64(len(sorted))
+	return Result{
+		Rows:             rows,
+		Gantt:            gantt,
+		AvgWait:          float64(totalWait) / count,
+		AvgTurnaround:    float64(totalTurnaround) / count,
+		Throughput:       count / float64(clock),
+		WaitDigest:       waitDigest,
+		TurnaroundDigest: turnaroundDigest,
+	}
+}
+
+// RoundRobin gives each ready process a slice of at most Quantum time
+// units before moving on to the next one, re-queuing it if it isn't done.
+type RoundRobin struct {
+	Quantum int64
 }
-//
-func RRSchedule(w io.Writer, title string, processes []Process) {
-	timeQuantum := 2
+
+func (rr RoundRobin) Name() string { return fmt.Sprintf("Round-robin (q=%d)", rr.Quantum) }
+
+func (rr RoundRobin) Schedule(processes []Process, hb *heartbeat) Result {
+	sorted := make([]Process, len(processes))
+	copy(sorted, processes)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ArrivalTime < sorted[j].ArrivalTime })
+
+	n := len(sorted)
+	remaining := make([]int64, n)
+	for i := range sorted {
+		remaining[i] = sorted[i].BurstDuration
+	}
+
 	var (
-		totalWait       int64
-		turnaround int64
-		currentTime     int64
+		waitOf, turnaroundOf = make([]int64, n), make([]int64, n)
+		queue                = make([]int, 0, n)
+		clock, completed     int64
+		gantt                []TimeSlice
+		next                 int
 	)
 
-	// Using a queue to handle processes
-	queue := make([]Process, 0)
+	for completed < int64(n) {
+		for next < n && sorted[next].ArrivalTime <= clock {
+			queue = append(queue, next)
+			next++
+		}
+		if len(queue) == 0 {
+			clock = sorted[next].ArrivalTime
+			continue
+		}
 
-	for len(queue) > 0 || len(processes) > 0 {
-		for i := 0; i < len(processes); {
-			if processes[i].ArrivalTime <= currentTime {
-				queue = append(queue, processes[i])
-				// Remove the added process from the original slice
-				processes = append(processes[:i], processes[i+1:]...)
-				continue
-			}
-			i++
+		idx := queue[0]
+		queue = queue[1:]
+
+		run := remaining[idx]
+		if run > rr.Quantum {
+			run = rr.Quantum
 		}
 
-		if len(queue) > 0 {
-			p := queue[0]
-			queue = queue[1:]
-
-			if p.BurstDuration > int64(timeQuantum) {
-				currentTime += int64(timeQuantum)
-				p.BurstDuration -= int64(timeQuantum)
-				queue = append(queue, p) // Add back to the queue if not finished
-			} else {
-				currentTime += p.BurstDuration
-				turnaround += currentTime - p.ArrivalTime
-				totalWait += currentTime - p.ArrivalTime - p.BurstDuration
-			}
+		start := clock
+		clock += run
+		remaining[idx] -= run
+		gantt = append(gantt, TimeSlice{PID: sorted[idx].ProcessID, Start: start, Stop: clock})
+		hb.tick(clock, len(queue), sorted[idx].ProcessID)
+
+		// Re-queue arrivals that landed mid-quantum before the process
+		// itself, so a process doesn't cut in front of work that showed
+		// up while it was running.
+		for next < n && sorted[next].ArrivalTime <= clock {
+			queue = append(queue, next)
+			next++
+		}
+
+		if remaining[idx] == 0 {
+			completed++
+			turnaroundOf[idx] = clock - sorted[idx].ArrivalTime
+			waitOf[idx] = turnaroundOf[idx] - sorted[idx].BurstDuration
 		} else {
-			currentTime++
+			queue = append(queue, idx)
 		}
 	}
 
-	// Calculating averages
-	avgTurnaround := float64(turnaround) / float64(len(processes))
-	avgWait := float64(totalWait) / float64(len(processes))
-	throughput := float64(len(processes)) / float64(currentTime)
+	var totalWait, totalTurnaround int64
+	rows := make([][]string, n)
+	waitDigest, turnaroundDigest := tdigest.New(), tdigest.New()
+	for i := range sorted {
+		totalWait += waitOf[i]
+		totalTurnaround += turnaroundOf[i]
+		waitDigest.Add(float64(waitOf[i]), 1)
+		turnaroundDigest.Add(float64(turnaroundOf[i]), 1)
+		rows[i] = processRow(sorted[i], waitOf[i], turnaroundOf[i], sorted[i].ArrivalTime+turnaroundOf[i])
+	}
 
-	// Output the results
-	fmt.Printf("Average Turnaround Time: %.2f\n", avgTurnaround)
-	fmt.Printf("Average Waiting Time: %.2f\n", avgWait)
-	fmt.Printf("Throughput: %.2f\n", throughput)
+	count := float64(n)
+	return Result{
+		Rows:             rows,
+		Gantt:            gantt,
+		AvgWait:          float64(totalWait) / count,
+		AvgTurnaround:    float64(totalTurnaround) / count,
+		Throughput:       count / float64(clock),
+		WaitDigest:       waitDigest,
+		TurnaroundDigest: turnaroundDigest,
+	}
+}
+
+// processRow renders one schedule table row in the ID/Priority/Burst/
+// Arrival/Wait/Turnaround/Exit column order every Scheduler shares.
+func processRow(p Process, wait, turnaround, exit int64) []string {
+	return []string{
+		fmt.Sprint(p.ProcessID),
+		fmt.Sprint(p.Priority),
+		fmt.Sprint(p.BurstDuration),
+		fmt.Sprint(p.ArrivalTime),
+		fmt.Sprint(wait),
+		fmt.Sprint(turnaround),
+		fmt.Sprint(exit),
+	}
 }
 
 //endregion
@@ -322,7 +678,115 @@ func outputGantt(w io.Writer, gantt []TimeSlice) {
 	_, _ = fmt.Fprintf(w, "\n\n")
 }
 
-func outputSchedule(w io.Writer, rows [][]string, wait, turnaround, throughput float64) {
+// outputResult renders a Scheduler's Result: the title, the ASCII Gantt
+// bar, the schedule table, and the tail latency quantiles. (-format=trace
+// bypasses this entirely; see outputTraceFile.)
+func outputResult(w io.Writer, title string, result Result) {
+	outputTitle(w, title)
+	outputGantt(w, result.Gantt)
+	outputSchedule(w, result.Rows, result.AvgWait, result.AvgTurnaround, result.Throughput, result.WaitDigest, result.TurnaroundDigest)
+}
+
+// traceUnit is the number of microseconds a single simulated time unit
+// represents in the emitted trace; chrome://tracing and Perfetto both expect
+// "ts"/"dur" in microseconds.
+const traceUnit = 1000
+
+// traceEvent is one record of the Chrome Trace Event Format.
+// See https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+// ts/dur intentionally have no omitempty: a slice legitimately starting at
+// simulated time 0 still needs its "ts" field written as 0, not dropped.
+type traceEvent struct {
+	Name     string         `json:"name"`
+	Phase    string         `json:"ph"`
+	Category string         `json:"cat,omitempty"`
+	PID      int64          `json:"pid"`
+	TID      int64          `json:"tid"`
+	Start    int64          `json:"ts"`
+	Duration int64          `json:"dur"`
+	Args     map[string]any `json:"args,omitempty"`
+}
+
+type traceFile struct {
+	Events          []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+// tracePID gives each (scheduler, core) pair its own Chrome trace "pid" so
+// every scheduler - and, under -cpus>1, every core - gets a distinct track
+// in the trace file instead of overlapping onto the same one. The pids stay
+// distinct only while there are fewer than 100 cores.
+func tracePID(schedulerIdx, coreID int) int64 {
+	return int64(schedulerIdx*100 + coreID)
+}
+
+// outputTraceFile renders every scheduler's timeline (or, with -cpus>1,
+// every scheduler's per-core timelines) as a single Chrome Trace Event
+// Format document on w with no surrounding banners or tables, so the
+// output loads directly into chrome://tracing or Perfetto instead of
+// being banners/tables interleaved with several concatenated JSON
+// documents.
+func outputTraceFile(w io.Writer, processes []Process, cpus int) error {
+	byPID := make(map[int64]Process, len(processes))
+	for _, p := range processes {
+		byPID[p.ProcessID] = p
+	}
+
+	var events []traceEvent
+	for idx, name := range schedulerOrder {
+		s := schedulers[name]
+		if cpus > 1 {
+			result := runMultiCPU(name, processes, cpus)
+			for _, core := range result.Cores {
+				pid := tracePID(idx, core.CoreID)
+				events = append(events, traceProcessNameEvent(pid, fmt.Sprintf("%s (core %d)", s.Name(), core.CoreID)))
+				events = append(events, traceSliceEvents(pid, name, core.Gantt, byPID)...)
+			}
+			continue
+		}
+
+		result := s.Schedule(processes, nil)
+		pid := tracePID(idx, 0)
+		events = append(events, traceProcessNameEvent(pid, s.Name()))
+		events = append(events, traceSliceEvents(pid, name, result.Gantt, byPID)...)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(traceFile{Events: events, DisplayTimeUnit: "ms"})
+}
+
+func traceProcessNameEvent(pid int64, name string) traceEvent {
+	return traceEvent{Name: "process_name", Phase: "M", PID: pid, Args: map[string]any{"name": name}}
+}
+
+// traceSliceEvents converts one scheduler/core's Gantt timeline into
+// Chrome trace "X" (complete event) records, one per TimeSlice, tagged
+// with the originating process's burst/arrival/priority - TimeSlice
+// itself only carries PID/Start/Stop, so those come from byPID.
+func traceSliceEvents(pid int64, schedulerName string, gantt []TimeSlice, byPID map[int64]Process) []traceEvent {
+	events := make([]traceEvent, 0, len(gantt))
+	for _, ts := range gantt {
+		p := byPID[ts.PID]
+		events = append(events, traceEvent{
+			Name:     fmt.Sprintf("P%d", ts.PID),
+			Phase:    "X",
+			Category: schedulerName,
+			PID:      pid,
+			TID:      ts.PID,
+			Start:    ts.Start * traceUnit,
+			Duration: (ts.Stop - ts.Start) * traceUnit,
+			Args: map[string]any{
+				"burst":    p.BurstDuration,
+				"arrival":  p.ArrivalTime,
+				"priority": p.Priority,
+			},
+		})
+	}
+	return events
+}
+
+func outputSchedule(w io.Writer, rows [][]string, wait, turnaround, throughput float64, waitDigest, turnaroundDigest *tdigest.Digest) {
 	_, _ = fmt.Fprintln(w, "Schedule table")
 	table := tablewriter.NewWriter(w)
 	table.SetHeader([]string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"})
@@ -332,6 +796,28 @@ func outputSchedule(w io.Writer, rows [][]string, wait, turnaround, throughput f
 		fmt.Sprintf("Average\n%.2f", turnaround),
 		fmt.Sprintf("Throughput\n%.2f/t", throughput)})
 	table.Render()
+
+	outputLatencyQuantiles(w, waitDigest, turnaroundDigest)
+}
+
+// outputLatencyQuantiles prints approximate p50/p90/p95/p99 wait and
+// turnaround times, computed from the streaming digests gathered while the
+// schedule ran, alongside the averages already shown in the table above.
+func outputLatencyQuantiles(w io.Writer, waitDigest, turnaroundDigest *tdigest.Digest) {
+	quantiles := []float64{0.5, 0.9, 0.95, 0.99}
+
+	_, _ = fmt.Fprintln(w, "Tail latencies (t-digest)")
+	_, _ = fmt.Fprint(w, "Wait       ")
+	for _, q := range quantiles {
+		_, _ = fmt.Fprintf(w, "p%.0f=%.2f  ", q*100, waitDigest.Quantile(q))
+	}
+	_, _ = fmt.Fprintln(w)
+
+	_, _ = fmt.Fprint(w, "Turnaround ")
+	for _, q := range quantiles {
+		_, _ = fmt.Fprintf(w, "p%.0f=%.2f  ", q*100, turnaroundDigest.Quantile(q))
+	}
+	_, _ = fmt.Fprintln(w)
 }
 
 //endregion
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Obstruct1/Project1/internal/tdigest"
+)
+
+// MultiCPUResult is the outcome of dispatching a workload across several
+// simulated cores: one Gantt lane per core, the metrics that only make
+// sense once more than one core is involved, and the same combined
+// schedule table/averages/tail latencies the single-CPU path reports, so
+// -cpus>1 isn't a strictly smaller report than -cpus=1.
+type MultiCPUResult struct {
+	Cores            []CoreResult
+	Makespan         int64
+	Throughput       float64
+	Rows             [][]string
+	AvgWait          float64
+	AvgTurnaround    float64
+	WaitDigest       *tdigest.Digest
+	TurnaroundDigest *tdigest.Digest
+}
+
+// CoreResult is a single core's lane of the Gantt timeline and how much of
+// the run it spent busy.
+type CoreResult struct {
+	CoreID      int
+	Gantt       []TimeSlice
+	Utilization float64
+}
+
+// readyOrder returns the order in which the dispatcher picks among the
+// processes that have arrived by the time a core frees up. FCFS and round
+// robin hand them out in arrival order;
+// the SJF family hands out the shortest burst first; priority hands out
+// the lowest Priority value first. Each core still respects a process's
+// own arrival time once it picks the process up, so a short job that
+// hasn't arrived yet can't jump ahead of one that already has.
+//
+// This is non-preemptive list scheduling regardless of schedulerName: once
+// a process is assigned to a core it runs to completion on that core. On
+// -cpus=1, SRTF and Priority preempt a running process for one that arrives
+// with a shorter remaining burst or higher priority (see SJFPreemptive and
+// PriorityPreemptive); on -cpus>1 they only affect dispatch order, so SRTF
+// degrades to ordinary SJF and Priority never preempts a core that's
+// already running something.
+func readyOrder(schedulerName string, processes []Process) []Process {
+	sorted := make([]Process, len(processes))
+	copy(sorted, processes)
+
+	switch schedulerName {
+	case "SJF", "SRTF":
+		sort.Slice(sorted, func(i, j int) bool {
+			if sorted[i].BurstDuration != sorted[j].BurstDuration {
+				return sorted[i].BurstDuration < sorted[j].BurstDuration
+			}
+			return sorted[i].ArrivalTime < sorted[j].ArrivalTime
+		})
+	case "Priority":
+		sort.Slice(sorted, func(i, j int) bool {
+			if sorted[i].Priority != sorted[j].Priority {
+				return sorted[i].Priority < sorted[j].Priority
+			}
+			return sorted[i].ArrivalTime < sorted[j].ArrivalTime
+		})
+	default: // FCFS, RR
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].ArrivalTime < sorted[j].ArrivalTime })
+	}
+
+	return sorted
+}
+
+// runMultiCPU dispatches processes to cpus simulated cores with a greedy,
+// non-preemptive list-scheduling policy (see readyOrder): whichever core's
+// own simulated clock is currently earliest gets the first process in ready
+// order that has arrived by then (or, if none has, by the next arrival
+// time). That keeps the assignment a pure function of the
+// input - deterministic and reproducible - unlike handing processes to
+// whichever core wins a real-time channel race, which depends on goroutine
+// scheduling and can leave cores idle that were "free" in simulated time
+// but lost the race.
+func runMultiCPU(schedulerName string, processes []Process, cpus int) MultiCPUResult {
+	ready := readyOrder(schedulerName, processes)
+
+	clocks := make([]int64, cpus)
+	busy := make([]int64, cpus)
+	gantt := make([][]TimeSlice, cpus)
+
+	rows := make([][]string, 0, len(ready))
+	waitDigest, turnaroundDigest := tdigest.New(), tdigest.New()
+	var totalWait, totalTurnaround int64
+
+	for len(ready) > 0 {
+		core := 0
+		for c := 1; c < cpus; c++ {
+			if clocks[c] < clocks[core] {
+				core = c
+			}
+		}
+
+		now, pick := clocks[core], -1
+		for pick < 0 {
+			earliest := ready[0].ArrivalTime
+			for i := range ready {
+				if ready[i].ArrivalTime <= now {
+					pick = i
+					break
+				} else if ready[i].ArrivalTime < earliest {
+					earliest = ready[i].ArrivalTime
+				}
+			}
+			now = earliest
+		}
+		p := ready[pick]
+		ready = append(ready[:pick], ready[pick+1:]...)
+
+		if p.ArrivalTime > clocks[core] {
+			clocks[core] = p.ArrivalTime
+		}
+		start := clocks[core]
+		wait := start - p.ArrivalTime
+		clocks[core] += p.BurstDuration
+		turnaround := wait + p.BurstDuration
+		busy[core] += p.BurstDuration
+
+		gantt[core] = append(gantt[core], TimeSlice{PID: p.ProcessID, Start: start, Stop: clocks[core]})
+		rows = append(rows, processRow(p, wait, turnaround, clocks[core]))
+
+		totalWait += wait
+		totalTurnaround += turnaround
+		waitDigest.Add(float64(wait), 1)
+		turnaroundDigest.Add(float64(turnaround), 1)
+	}
+
+	cores := make([]CoreResult, cpus)
+	var makespan int64
+	for c := 0; c < cpus; c++ {
+		cores[c] = CoreResult{CoreID: c, Gantt: gantt[c], Utilization: utilizationPct(busy[c], clocks[c])}
+		if clocks[c] > makespan {
+			makespan = clocks[c]
+		}
+	}
+
+	count := float64(len(processes))
+	return MultiCPUResult{
+		Cores:            cores,
+		Makespan:         makespan,
+		Throughput:       count / float64(makespan),
+		Rows:             rows,
+		AvgWait:          float64(totalWait) / count,
+		AvgTurnaround:    float64(totalTurnaround) / count,
+		WaitDigest:       waitDigest,
+		TurnaroundDigest: turnaroundDigest,
+	}
+}
+
+func utilizationPct(busy, clock int64) float64 {
+	if clock == 0 {
+		return 0
+	}
+	return float64(busy) / float64(clock) * 100
+}
+
+// outputMultiCPUResult renders one Gantt lane per core along with each
+// core's utilization and the overall makespan/throughput, followed by the
+// same combined schedule table and tail latencies the single-CPU path
+// prints. (-format=trace bypasses this entirely; see outputTraceFile.)
+func outputMultiCPUResult(w io.Writer, title string, result MultiCPUResult) {
+	outputTitle(w, title)
+
+	for _, core := range result.Cores {
+		_, _ = fmt.Fprintf(w, "Core %d (utilization %.1f%%)\n", core.CoreID, core.Utilization)
+		outputGantt(w, core.Gantt)
+	}
+
+	_, _ = fmt.Fprintf(w, "Makespan: %d   Throughput: %.2f/t\n\n", result.Makespan, result.Throughput)
+	outputSchedule(w, result.Rows, result.AvgWait, result.AvgTurnaround, result.Throughput, result.WaitDigest, result.TurnaroundDigest)
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Snapshot is a point-in-time view of a running simulation, served over
+// -status-addr so a user running a large simulation can check progress
+// without waiting for the final table. It's plain JSON so it's scriptable
+// without the `ps` subcommand.
+type Snapshot struct {
+	Host       string    `json:"host"`
+	PID        int       `json:"pid"`
+	Scheduler  string    `json:"scheduler"`
+	Clock      int64     `json:"clock"`
+	Completed  int64     `json:"completed"`
+	Remaining  int64     `json:"remaining"`
+	ReadyDepth int64     `json:"ready_depth"`
+	Running    []int64   `json:"running"`
+	Throughput float64   `json:"throughput"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// heartbeat tracks a running simulation's progress with atomic counters
+// and periodically refreshes a Snapshot for serveStatus to hand out.
+type heartbeat struct {
+	total int64
+
+	clock      int64
+	completed  int64
+	readyDepth int64
+
+	mu        sync.Mutex
+	scheduler string
+	running   []int64
+	snapshot  Snapshot
+}
+
+func newHeartbeat(scheduler string, total int64) *heartbeat {
+	return &heartbeat{scheduler: scheduler, total: total}
+}
+
+func (h *heartbeat) setClock(c int64)      { atomic.StoreInt64(&h.clock, c) }
+func (h *heartbeat) setReadyDepth(n int64) { atomic.StoreInt64(&h.readyDepth, n) }
+func (h *heartbeat) addCompleted(n int64)  { atomic.AddInt64(&h.completed, n) }
+
+// setScheduler records which scheduler is currently running, for runs
+// (like main's single-CPU loop) that work through several in sequence.
+func (h *heartbeat) setScheduler(name string) {
+	h.mu.Lock()
+	h.scheduler = name
+	h.mu.Unlock()
+}
+
+// setRunning records which PIDs are currently executing.
+func (h *heartbeat) setRunning(pids ...int64) {
+	h.mu.Lock()
+	h.running = append(h.running[:0], pids...)
+	h.mu.Unlock()
+}
+
+// tick is the per-iteration progress hook a Scheduler.Schedule pass calls
+// as it runs, so a long simulation's clock/ready-depth/running-PID(s)
+// show up in the snapshot instead of sitting at zero until the whole pass
+// finishes. h may be nil (no -status-addr set, or a caller - like bench,
+// which runs several schedulers concurrently and would otherwise clobber
+// a shared clock/running state - that doesn't wire it in); tick on a nil
+// *heartbeat is a no-op, while the other methods need a non-nil h.
+func (h *heartbeat) tick(clock int64, ready int, running ...int64) {
+	if h == nil {
+		return
+	}
+	h.setClock(clock)
+	h.setReadyDepth(int64(ready))
+	h.setRunning(running...)
+}
+
+// run refreshes the snapshot every interval until done is closed, so a
+// concurrent `scheduler ps` never sees data older than interval.
+func (h *heartbeat) run(done <-chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.refresh()
+		case <-done:
+			h.refresh()
+			return
+		}
+	}
+}
+
+func (h *heartbeat) refresh() {
+	completed := atomic.LoadInt64(&h.completed)
+	clock := atomic.LoadInt64(&h.clock)
+
+	var throughput float64
+	if clock > 0 {
+		throughput = float64(completed) / float64(clock)
+	}
+
+	h.mu.Lock()
+	scheduler := h.scheduler
+	running := append([]int64(nil), h.running...)
+	h.mu.Unlock()
+
+	host, _ := os.Hostname()
+	snap := Snapshot{
+		Host:       host,
+		PID:        os.Getpid(),
+		Scheduler:  scheduler,
+		Clock:      clock,
+		Completed:  completed,
+		Remaining:  h.total - completed,
+		ReadyDepth: atomic.LoadInt64(&h.readyDepth),
+		Running:    running,
+		Throughput: throughput,
+		UpdatedAt:  time.Now(),
+	}
+
+	h.mu.Lock()
+	h.snapshot = snap
+	h.mu.Unlock()
+}
+
+func (h *heartbeat) Snapshot() Snapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.snapshot
+}
+
+// serveStatus starts an HTTP server on addr exposing h's snapshot as JSON
+// at /status, until done is closed.
+func serveStatus(addr string, h *heartbeat, done <-chan struct{}) error {
+	h.refresh()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(h.Snapshot())
+	})
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		<-done
+		_ = server.Close()
+	}()
+
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// runPS implements the `ps` subcommand: it polls one or more -status-addr
+// endpoints and prints what it finds as a table, so multiple concurrent
+// simulations are discoverable in one place.
+func runPS(args []string) error {
+	fs := flag.NewFlagSet("ps", flag.ExitOnError)
+	addrs := fs.String("addrs", "localhost:8080", "comma-separated list of -status-addr endpoints to poll")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var snapshots []Snapshot
+	for _, addr := range strings.Split(*addrs, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		snap, err := fetchSnapshot(addr)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "ps: %s: %v\n", addr, err)
+			continue
+		}
+		snapshots = append(snapshots, snap)
+	}
+
+	outputPSTable(os.Stdout, snapshots)
+	return nil
+}
+
+func fetchSnapshot(addr string) (Snapshot, error) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/status", addr))
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer resp.Body.Close()
+
+	var snap Snapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snap); err != nil {
+		return Snapshot{}, fmt.Errorf("%w: decoding status response", err)
+	}
+	return snap, nil
+}
+
+func outputPSTable(w io.Writer, snapshots []Snapshot) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Host", "PID", "Scheduler", "Clock", "Completed", "Remaining", "Ready", "Running", "Throughput", "Updated"})
+	for _, s := range snapshots {
+		table.Append([]string{
+			s.Host,
+			fmt.Sprint(s.PID),
+			s.Scheduler,
+			fmt.Sprint(s.Clock),
+			fmt.Sprint(s.Completed),
+			fmt.Sprint(s.Remaining),
+			fmt.Sprint(s.ReadyDepth),
+			fmt.Sprint(s.Running),
+			fmt.Sprintf("%.2f/t", s.Throughput),
+			s.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	table.Render()
+}